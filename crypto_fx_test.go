@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func TestNormalizeCryptoSymbol(t *testing.T) {
+    tests := []struct {
+        name   string
+        symbol string
+        want   string
+    }{
+        {"already qualified passes through", "BTC-USD", "BTC-USD"},
+        {"bare ticker gets -USD appended", "BTC", "BTC-USD"},
+        {"already qualified with non-USD quote currency", "ETH-EUR", "ETH-EUR"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := normalizeCryptoSymbol(tt.symbol); got != tt.want {
+                t.Fatalf("normalizeCryptoSymbol(%q) = %q, want %q", tt.symbol, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCryptoSymbolPattern(t *testing.T) {
+    tests := []struct {
+        symbol string
+        want   bool
+    }{
+        {"BTC-USD", true},
+        {"ETH-USD", true},
+        {"BTC-USDT", true},
+        {"!!-USD", false},
+        {"BTCUSD", false},
+        {"-USD", false},
+        {"BTC-", false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.symbol, func(t *testing.T) {
+            if got := cryptoSymbolPattern.MatchString(tt.symbol); got != tt.want {
+                t.Fatalf("cryptoSymbolPattern.MatchString(%q) = %v, want %v", tt.symbol, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestNormalizeFXSymbol(t *testing.T) {
+    tests := []struct {
+        name   string
+        symbol string
+        want   string
+    }{
+        {"already qualified passes through", "EURUSD=X", "EURUSD=X"},
+        {"slash-separated pair is rewritten", "EUR/USD", "EURUSD=X"},
+        {"bare pair gets =X appended", "EURUSD", "EURUSD=X"},
+        {"single-currency shorthand passes through", "JPY=X", "JPY=X"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := normalizeFXSymbol(tt.symbol); got != tt.want {
+                t.Fatalf("normalizeFXSymbol(%q) = %q, want %q", tt.symbol, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestFXSymbolPattern(t *testing.T) {
+    tests := []struct {
+        symbol string
+        want   bool
+    }{
+        {"EURUSD=X", true},
+        {"JPY=X", true},
+        {"EURUSD", false},
+        {"=X", false},
+        {"TOOLONGPAIR=X", false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.symbol, func(t *testing.T) {
+            if got := fxSymbolPattern.MatchString(tt.symbol); got != tt.want {
+                t.Fatalf("fxSymbolPattern.MatchString(%q) = %v, want %v", tt.symbol, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,89 @@
+package providers
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "time"
+)
+
+// AlpacaProvider implements PriceProvider against the Alpaca market data
+// snapshot API.
+type AlpacaProvider struct {
+    keyID     string
+    secretKey string
+    client    *http.Client
+}
+
+func NewAlpacaProvider(keyID, secretKey string) *AlpacaProvider {
+    return &AlpacaProvider{
+        keyID:     keyID,
+        secretKey: secretKey,
+        client:    &http.Client{Timeout: 10 * time.Second},
+    }
+}
+
+func (p *AlpacaProvider) Name() string { return "alpaca" }
+
+func (p *AlpacaProvider) Quote(ctx context.Context, symbol string) (Quote, error) {
+    if p.keyID == "" || p.secretKey == "" {
+        return Quote{}, fmt.Errorf("alpaca: missing API credentials")
+    }
+
+    endpoint := fmt.Sprintf("https://data.alpaca.markets/v2/stocks/%s/snapshot", url.PathEscape(symbol))
+
+    req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+    if err != nil {
+        return Quote{}, err
+    }
+    req.Header.Set("APCA-API-KEY-ID", p.keyID)
+    req.Header.Set("APCA-API-SECRET-KEY", p.secretKey)
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return Quote{}, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return Quote{}, fmt.Errorf("alpaca: status %d", resp.StatusCode)
+    }
+
+    var data struct {
+        LatestTrade struct {
+            Price float64 `json:"p"`
+        } `json:"latestTrade"`
+        PrevDailyBar struct {
+            Close float64 `json:"c"`
+        } `json:"prevDailyBar"`
+        DailyBar struct {
+            High   float64 `json:"h"`
+            Low    float64 `json:"l"`
+            Volume float64 `json:"v"`
+        } `json:"dailyBar"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+        return Quote{}, err
+    }
+
+    if data.LatestTrade.Price == 0 {
+        return Quote{}, fmt.Errorf("alpaca: no data for symbol: %s", symbol)
+    }
+
+    return Quote{
+        Symbol:        symbol,
+        Price:         data.LatestTrade.Price,
+        PreviousClose: data.PrevDailyBar.Close,
+        DayHigh:       data.DailyBar.High,
+        DayLow:        data.DailyBar.Low,
+        Volume:        data.DailyBar.Volume,
+    }, nil
+}
+
+// Historical is unimplemented: Alpaca's bars endpoint needs a separate
+// symbol/timeframe mapping from Yahoo's; left for a future change.
+func (p *AlpacaProvider) Historical(ctx context.Context, symbol, rangeArg, interval string) ([]Bar, error) {
+    return nil, fmt.Errorf("alpaca: historical prices not supported")
+}
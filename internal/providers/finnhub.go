@@ -0,0 +1,77 @@
+package providers
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "time"
+)
+
+// FinnhubProvider implements PriceProvider against the Finnhub quote API.
+type FinnhubProvider struct {
+    apiKey string
+    client *http.Client
+}
+
+func NewFinnhubProvider(apiKey string) *FinnhubProvider {
+    return &FinnhubProvider{
+        apiKey: apiKey,
+        client: &http.Client{Timeout: 10 * time.Second},
+    }
+}
+
+func (p *FinnhubProvider) Name() string { return "finnhub" }
+
+func (p *FinnhubProvider) Quote(ctx context.Context, symbol string) (Quote, error) {
+    if p.apiKey == "" {
+        return Quote{}, fmt.Errorf("finnhub: missing API key")
+    }
+
+    endpoint := fmt.Sprintf("https://finnhub.io/api/v1/quote?symbol=%s&token=%s",
+        url.QueryEscape(symbol), url.QueryEscape(p.apiKey))
+
+    req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+    if err != nil {
+        return Quote{}, err
+    }
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return Quote{}, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return Quote{}, fmt.Errorf("finnhub: status %d", resp.StatusCode)
+    }
+
+    var data struct {
+        CurrentPrice  float64 `json:"c"`
+        PreviousClose float64 `json:"pc"`
+        High          float64 `json:"h"`
+        Low           float64 `json:"l"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+        return Quote{}, err
+    }
+
+    if data.CurrentPrice == 0 {
+        return Quote{}, fmt.Errorf("finnhub: no data for symbol: %s", symbol)
+    }
+
+    return Quote{
+        Symbol:        symbol,
+        Price:         data.CurrentPrice,
+        PreviousClose: data.PreviousClose,
+        DayHigh:       data.High,
+        DayLow:        data.Low,
+    }, nil
+}
+
+// Historical is unimplemented: Finnhub's candle endpoint requires a paid
+// plan for most symbols, so this provider is quote-only for now.
+func (p *FinnhubProvider) Historical(ctx context.Context, symbol, rangeArg, interval string) ([]Bar, error) {
+    return nil, fmt.Errorf("finnhub: historical prices not supported")
+}
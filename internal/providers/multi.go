@@ -0,0 +1,97 @@
+package providers
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "time"
+)
+
+// multiProviderTimeout bounds how long any single backend gets before its
+// result is discarded in favor of whichever provider answers first.
+const multiProviderTimeout = 10 * time.Second
+
+// multiProvider races every configured backend and returns the first
+// successful result, which gives callers a path forward when Yahoo
+// rate-limits or is otherwise unavailable.
+type multiProvider struct {
+    providers []PriceProvider
+    timeout   time.Duration
+}
+
+// NewMultiProvider builds a provider that races Yahoo, Finnhub, and Alpaca.
+func NewMultiProvider() (PriceProvider, error) {
+    yahoo, err := NewYahooProvider()
+    if err != nil {
+        return nil, err
+    }
+
+    return &multiProvider{
+        providers: []PriceProvider{
+            yahoo,
+            NewFinnhubProvider(os.Getenv("FINNHUB_API_KEY")),
+            NewAlpacaProvider(os.Getenv("APCA_API_KEY_ID"), os.Getenv("APCA_API_SECRET_KEY")),
+        },
+        timeout: multiProviderTimeout,
+    }, nil
+}
+
+func (m *multiProvider) Name() string { return "multi" }
+
+func (m *multiProvider) Quote(ctx context.Context, symbol string) (Quote, error) {
+    type result struct {
+        quote Quote
+        err   error
+    }
+
+    results := make(chan result, len(m.providers))
+    for _, p := range m.providers {
+        p := p
+        go func() {
+            pctx, cancel := context.WithTimeout(ctx, m.timeout)
+            defer cancel()
+            quote, err := p.Quote(pctx, symbol)
+            results <- result{quote: quote, err: err}
+        }()
+    }
+
+    var lastErr error
+    for range m.providers {
+        r := <-results
+        if r.err == nil {
+            return r.quote, nil
+        }
+        lastErr = r.err
+    }
+
+    return Quote{}, fmt.Errorf("multi: all providers failed: %w", lastErr)
+}
+
+func (m *multiProvider) Historical(ctx context.Context, symbol, rangeArg, interval string) ([]Bar, error) {
+    type result struct {
+        bars []Bar
+        err  error
+    }
+
+    results := make(chan result, len(m.providers))
+    for _, p := range m.providers {
+        p := p
+        go func() {
+            pctx, cancel := context.WithTimeout(ctx, m.timeout)
+            defer cancel()
+            bars, err := p.Historical(pctx, symbol, rangeArg, interval)
+            results <- result{bars: bars, err: err}
+        }()
+    }
+
+    var lastErr error
+    for range m.providers {
+        r := <-results
+        if r.err == nil {
+            return r.bars, nil
+        }
+        lastErr = r.err
+    }
+
+    return nil, fmt.Errorf("multi: all providers failed: %w", lastErr)
+}
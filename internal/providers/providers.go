@@ -0,0 +1,75 @@
+// Package providers abstracts the market data backend behind a common
+// PriceProvider interface so the server can be pointed at Yahoo Finance,
+// Finnhub, Alpaca, or a combination of all three.
+package providers
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "strings"
+)
+
+// Quote is a normalized snapshot of a symbol's current price and, where the
+// backing provider exposes them, its fundamentals.
+type Quote struct {
+    Symbol        string
+    Price         float64
+    PreviousClose float64
+    DayHigh       float64
+    DayLow        float64
+    Volume        float64
+    MarketState   string
+
+    LongName         string
+    Exchange         string
+    MarketCap        float64
+    PERatio          float64
+    EPS              float64
+    Bid              float64
+    Ask              float64
+    FiftyTwoWeekLow  float64
+    FiftyTwoWeekHigh float64
+    DividendYield    float64
+}
+
+// Bar is a single open/high/low/close/volume price bar.
+type Bar struct {
+    Timestamp int64
+    Open      float64
+    High      float64
+    Low       float64
+    Close     float64
+    Volume    float64
+}
+
+// PriceProvider is implemented by each backing market data source.
+type PriceProvider interface {
+    Name() string
+    Quote(ctx context.Context, symbol string) (Quote, error)
+    Historical(ctx context.Context, symbol, rangeArg, interval string) ([]Bar, error)
+}
+
+// BatchQuoter is implemented by providers that can fetch several symbols in
+// a single round trip; callers should prefer it over N calls to Quote.
+type BatchQuoter interface {
+    QuoteMany(ctx context.Context, symbols ...string) (map[string]Quote, error)
+}
+
+// New selects a provider based on the STOCK_MCP_PROVIDER environment
+// variable ("yahoo", "finnhub", "alpaca", or "multi"), defaulting to
+// "yahoo" when unset.
+func New() (PriceProvider, error) {
+    switch name := strings.ToLower(strings.TrimSpace(os.Getenv("STOCK_MCP_PROVIDER"))); name {
+    case "", "yahoo":
+        return NewYahooProvider()
+    case "finnhub":
+        return NewFinnhubProvider(os.Getenv("FINNHUB_API_KEY")), nil
+    case "alpaca":
+        return NewAlpacaProvider(os.Getenv("APCA_API_KEY_ID"), os.Getenv("APCA_API_SECRET_KEY")), nil
+    case "multi":
+        return NewMultiProvider()
+    default:
+        return nil, fmt.Errorf("providers: unknown provider %q", name)
+    }
+}
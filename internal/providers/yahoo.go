@@ -0,0 +1,412 @@
+package providers
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/cookiejar"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+)
+
+const yahooUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
+// crumbTTL controls how long a Yahoo crumb is reused before being refreshed.
+const crumbTTL = time.Hour
+
+// YahooProvider implements PriceProvider against Yahoo Finance, handling the
+// cookie/crumb handshake the v7 quote endpoint requires and falling back to
+// the unauthenticated v8 chart endpoint when that handshake fails.
+type YahooProvider struct {
+    client *http.Client
+
+    mu        sync.Mutex
+    crumb     string
+    fetchedAt time.Time
+}
+
+func NewYahooProvider() (*YahooProvider, error) {
+    jar, err := cookiejar.New(nil)
+    if err != nil {
+        return nil, err
+    }
+
+    return &YahooProvider{
+        client: &http.Client{
+            Timeout: 10 * time.Second,
+            Jar:     jar,
+        },
+    }, nil
+}
+
+func (p *YahooProvider) Name() string { return "yahoo" }
+
+// ensureCrumb refreshes the cached crumb if it is missing or stale.
+func (p *YahooProvider) ensureCrumb() error {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    if p.crumb != "" && time.Since(p.fetchedAt) < crumbTTL {
+        return nil
+    }
+
+    return p.refreshLocked()
+}
+
+// refreshLocked performs the cookie/crumb handshake. Callers must hold p.mu.
+func (p *YahooProvider) refreshLocked() error {
+    cookieReq, err := http.NewRequest("GET", "https://fc.yahoo.com", nil)
+    if err != nil {
+        return err
+    }
+    cookieReq.Header.Set("User-Agent", yahooUserAgent)
+
+    cookieResp, err := p.client.Do(cookieReq)
+    if err != nil {
+        return err
+    }
+    cookieResp.Body.Close()
+
+    crumbReq, err := http.NewRequest("GET", "https://query1.finance.yahoo.com/v1/test/getcrumb", nil)
+    if err != nil {
+        return err
+    }
+    crumbReq.Header.Set("User-Agent", yahooUserAgent)
+
+    crumbResp, err := p.client.Do(crumbReq)
+    if err != nil {
+        return err
+    }
+    defer crumbResp.Body.Close()
+
+    body, err := io.ReadAll(crumbResp.Body)
+    if err != nil {
+        return err
+    }
+
+    crumb := strings.TrimSpace(string(body))
+    if crumbResp.StatusCode != http.StatusOK || crumb == "" {
+        return fmt.Errorf("yahoo: failed to fetch crumb: status %d", crumbResp.StatusCode)
+    }
+
+    p.crumb = crumb
+    p.fetchedAt = time.Now()
+    return nil
+}
+
+// QuoteMany fetches the v7 quote payload for several symbols in a single
+// request, transparently refreshing the crumb once if Yahoo reports it as
+// expired.
+func (p *YahooProvider) QuoteMany(ctx context.Context, symbols ...string) (map[string]Quote, error) {
+    if err := p.ensureCrumb(); err != nil {
+        return nil, err
+    }
+
+    data, status, err := p.doQuote(ctx, symbols...)
+    if err != nil {
+        return nil, err
+    }
+
+    if status == http.StatusUnauthorized || status == http.StatusForbidden {
+        p.mu.Lock()
+        p.crumb = ""
+        refreshErr := p.refreshLocked()
+        p.mu.Unlock()
+        if refreshErr != nil {
+            return nil, refreshErr
+        }
+
+        data, status, err = p.doQuote(ctx, symbols...)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    if status != http.StatusOK {
+        return nil, fmt.Errorf("yahoo: quote request failed: status %d", status)
+    }
+
+    return parseQuoteResponse(data), nil
+}
+
+func (p *YahooProvider) doQuote(ctx context.Context, symbols ...string) (map[string]interface{}, int, error) {
+    p.mu.Lock()
+    crumb := p.crumb
+    p.mu.Unlock()
+
+    endpoint := fmt.Sprintf("https://query1.finance.yahoo.com/v7/finance/quote?symbols=%s&crumb=%s",
+        url.QueryEscape(strings.Join(symbols, ",")), url.QueryEscape(crumb))
+
+    req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+    if err != nil {
+        return nil, 0, err
+    }
+    req.Header.Set("User-Agent", yahooUserAgent)
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return nil, 0, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return nil, resp.StatusCode, err
+    }
+
+    var data map[string]interface{}
+    if err := json.Unmarshal(body, &data); err != nil {
+        return nil, resp.StatusCode, err
+    }
+
+    return data, resp.StatusCode, nil
+}
+
+// Quote fetches a single symbol, preferring the v7 quote endpoint and
+// falling back to the v8 chart endpoint if the crumb handshake fails.
+func (p *YahooProvider) Quote(ctx context.Context, symbol string) (Quote, error) {
+    if quotes, err := p.QuoteMany(ctx, symbol); err == nil {
+        if q, ok := quotes[symbol]; ok {
+            return q, nil
+        }
+    }
+
+    return p.chartQuote(ctx, symbol)
+}
+
+func (p *YahooProvider) chartQuote(ctx context.Context, symbol string) (Quote, error) {
+    endpoint := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s", symbol)
+
+    req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+    if err != nil {
+        return Quote{}, err
+    }
+    req.Header.Set("User-Agent", yahooUserAgent)
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return Quote{}, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return Quote{}, err
+    }
+
+    var data map[string]interface{}
+    if err := json.Unmarshal(body, &data); err != nil {
+        return Quote{}, err
+    }
+
+    chart, ok := data["chart"].(map[string]interface{})
+    if !ok {
+        return Quote{}, fmt.Errorf("yahoo: missing chart field for symbol: %s", symbol)
+    }
+
+    results, ok := chart["result"].([]interface{})
+    if !ok || len(results) == 0 {
+        return Quote{}, fmt.Errorf("yahoo: no chart data for symbol: %s", symbol)
+    }
+
+    result, ok := results[0].(map[string]interface{})
+    if !ok {
+        return Quote{}, fmt.Errorf("yahoo: invalid chart result for symbol: %s", symbol)
+    }
+
+    meta, ok := result["meta"].(map[string]interface{})
+    if !ok {
+        return Quote{}, fmt.Errorf("yahoo: missing chart meta for symbol: %s", symbol)
+    }
+
+    price, _ := meta["regularMarketPrice"].(float64)
+    if price == 0 {
+        return Quote{}, fmt.Errorf("yahoo: no price data for symbol: %s", symbol)
+    }
+
+    previousClose, _ := meta["previousClose"].(float64)
+    dayHigh, _ := meta["regularMarketDayHigh"].(float64)
+    dayLow, _ := meta["regularMarketDayLow"].(float64)
+    volume, _ := meta["regularMarketVolume"].(float64)
+
+    return Quote{
+        Symbol:        symbol,
+        Price:         price,
+        PreviousClose: previousClose,
+        DayHigh:       dayHigh,
+        DayLow:        dayLow,
+        Volume:        volume,
+    }, nil
+}
+
+// parseQuoteResponse converts a v7 quoteResponse payload into Quotes keyed
+// by symbol.
+func parseQuoteResponse(data map[string]interface{}) map[string]Quote {
+    quotes := make(map[string]Quote)
+
+    quoteResponse, ok := data["quoteResponse"].(map[string]interface{})
+    if !ok {
+        return quotes
+    }
+
+    results, ok := quoteResponse["result"].([]interface{})
+    if !ok {
+        return quotes
+    }
+
+    for _, r := range results {
+        entry, ok := r.(map[string]interface{})
+        if !ok {
+            continue
+        }
+
+        symbol, _ := entry["symbol"].(string)
+        price, _ := entry["regularMarketPrice"].(float64)
+        if symbol == "" || price == 0 {
+            continue
+        }
+
+        previousClose, _ := entry["regularMarketPreviousClose"].(float64)
+        dayHigh, _ := entry["regularMarketDayHigh"].(float64)
+        dayLow, _ := entry["regularMarketDayLow"].(float64)
+        volume, _ := entry["regularMarketVolume"].(float64)
+
+        quotes[symbol] = Quote{
+            Symbol:           symbol,
+            Price:            price,
+            PreviousClose:    previousClose,
+            DayHigh:          dayHigh,
+            DayLow:           dayLow,
+            Volume:           volume,
+            MarketState:      stringField(entry, "marketState"),
+            LongName:         stringField(entry, "longName"),
+            Exchange:         stringField(entry, "fullExchangeName"),
+            MarketCap:        floatField(entry, "marketCap"),
+            PERatio:          floatField(entry, "trailingPE"),
+            EPS:              floatField(entry, "epsTrailingTwelveMonths"),
+            Bid:              floatField(entry, "bid"),
+            Ask:              floatField(entry, "ask"),
+            FiftyTwoWeekLow:  floatField(entry, "fiftyTwoWeekLow"),
+            FiftyTwoWeekHigh: floatField(entry, "fiftyTwoWeekHigh"),
+            DividendYield:    floatField(entry, "trailingAnnualDividendYield"),
+        }
+    }
+
+    return quotes
+}
+
+func stringField(m map[string]interface{}, key string) string {
+    v, _ := m[key].(string)
+    return v
+}
+
+func floatField(m map[string]interface{}, key string) float64 {
+    v, _ := m[key].(float64)
+    return v
+}
+
+// Historical fetches OHLCV bars from the v8 chart endpoint.
+func (p *YahooProvider) Historical(ctx context.Context, symbol, rangeArg, interval string) ([]Bar, error) {
+    endpoint := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?range=%s&interval=%s", symbol, rangeArg, interval)
+
+    req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+    if err != nil {
+        return nil, err
+    }
+    req.Header.Set("User-Agent", yahooUserAgent)
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return nil, err
+    }
+
+    return parseChartBars(body)
+}
+
+// parseChartBars parses a v8 chart API response into OHLCV bars.
+func parseChartBars(body []byte) ([]Bar, error) {
+    var data map[string]interface{}
+    if err := json.Unmarshal(body, &data); err != nil {
+        return nil, err
+    }
+
+    chart, ok := data["chart"].(map[string]interface{})
+    if !ok {
+        return nil, fmt.Errorf("missing chart field")
+    }
+
+    results, ok := chart["result"].([]interface{})
+    if !ok || len(results) == 0 {
+        return nil, fmt.Errorf("missing chart result")
+    }
+
+    result, ok := results[0].(map[string]interface{})
+    if !ok {
+        return nil, fmt.Errorf("invalid chart result")
+    }
+
+    timestamps, ok := result["timestamp"].([]interface{})
+    if !ok {
+        return nil, fmt.Errorf("missing timestamp series")
+    }
+
+    indicators, ok := result["indicators"].(map[string]interface{})
+    if !ok {
+        return nil, fmt.Errorf("missing indicators")
+    }
+
+    quotes, ok := indicators["quote"].([]interface{})
+    if !ok || len(quotes) == 0 {
+        return nil, fmt.Errorf("missing quote indicators")
+    }
+
+    quote, ok := quotes[0].(map[string]interface{})
+    if !ok {
+        return nil, fmt.Errorf("invalid quote indicators")
+    }
+
+    opens, _ := quote["open"].([]interface{})
+    highs, _ := quote["high"].([]interface{})
+    lows, _ := quote["low"].([]interface{})
+    closes, _ := quote["close"].([]interface{})
+    volumes, _ := quote["volume"].([]interface{})
+
+    bars := make([]Bar, 0, len(timestamps))
+    for i, ts := range timestamps {
+        tsFloat, ok := ts.(float64)
+        if !ok {
+            continue
+        }
+
+        bars = append(bars, Bar{
+            Timestamp: int64(tsFloat),
+            Open:      floatAt(opens, i),
+            High:      floatAt(highs, i),
+            Low:       floatAt(lows, i),
+            Close:     floatAt(closes, i),
+            Volume:    floatAt(volumes, i),
+        })
+    }
+
+    return bars, nil
+}
+
+// floatAt safely reads the float64 at index i, returning 0 for a nil or
+// out-of-range entry (Yahoo pads gaps in the series with nulls).
+func floatAt(values []interface{}, i int) float64 {
+    if i >= len(values) {
+        return 0
+    }
+    v, _ := values[i].(float64)
+    return v
+}
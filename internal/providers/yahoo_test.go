@@ -0,0 +1,104 @@
+package providers
+
+import "testing"
+
+func TestParseChartBars(t *testing.T) {
+    tests := []struct {
+        name    string
+        body    string
+        wantLen int
+        wantErr bool
+    }{
+        {
+            name: "well-formed bars",
+            body: `{"chart":{"result":[{"timestamp":[1000,2000],"indicators":{"quote":[{
+                "open":[1.1,2.1],"high":[1.5,2.5],"low":[1.0,2.0],"close":[1.4,2.4],"volume":[100,200]
+            }]}}]}}`,
+            wantLen: 2,
+        },
+        {
+            name: "null-padded gap in a series",
+            body: `{"chart":{"result":[{"timestamp":[1000,2000],"indicators":{"quote":[{
+                "open":[1.1,null],"high":[1.5,2.5],"low":[1.0,2.0],"close":[1.4,null],"volume":[100,null]
+            }]}}]}}`,
+            wantLen: 2,
+        },
+        {
+            name:    "non-float timestamp entries are skipped",
+            body:    `{"chart":{"result":[{"timestamp":[1000,"bad",2000],"indicators":{"quote":[{"close":[1.4,9.9,2.4]}]}}]}}`,
+            wantLen: 2,
+        },
+        {
+            name:    "missing chart field",
+            body:    `{}`,
+            wantErr: true,
+        },
+        {
+            name:    "empty result array",
+            body:    `{"chart":{"result":[]}}`,
+            wantErr: true,
+        },
+        {
+            name:    "missing timestamp series",
+            body:    `{"chart":{"result":[{"indicators":{"quote":[{}]}}]}}`,
+            wantErr: true,
+        },
+        {
+            name:    "missing indicators",
+            body:    `{"chart":{"result":[{"timestamp":[1000]}]}}`,
+            wantErr: true,
+        },
+        {
+            name:    "missing quote indicators",
+            body:    `{"chart":{"result":[{"timestamp":[1000],"indicators":{"quote":[]}}]}}`,
+            wantErr: true,
+        },
+        {
+            name:    "malformed JSON",
+            body:    `{"chart":`,
+            wantErr: true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            bars, err := parseChartBars([]byte(tt.body))
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("expected an error, got bars: %+v", bars)
+                }
+                return
+            }
+
+            if err != nil {
+                t.Fatalf("unexpected error: %v", err)
+            }
+            if len(bars) != tt.wantLen {
+                t.Fatalf("got %d bars, want %d", len(bars), tt.wantLen)
+            }
+        })
+    }
+}
+
+func TestFloatAt(t *testing.T) {
+    values := []interface{}{1.5, nil, "not-a-float"}
+
+    tests := []struct {
+        name string
+        i    int
+        want float64
+    }{
+        {"in range float", 0, 1.5},
+        {"in range nil", 1, 0},
+        {"in range wrong type", 2, 0},
+        {"out of range", 3, 0},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := floatAt(values, tt.i); got != tt.want {
+                t.Fatalf("floatAt(values, %d) = %v, want %v", tt.i, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,36 @@
+// Package rpc defines the JSON-RPC 2.0 envelope shared by every MCP
+// transport, so the server's request handling stays transport-agnostic.
+package rpc
+
+// Request is a single JSON-RPC request.
+type Request struct {
+    JSONRPC string      `json:"jsonrpc"`
+    ID      interface{} `json:"id"`
+    Method  string      `json:"method"`
+    Params  interface{} `json:"params,omitempty"`
+}
+
+// Response is a single JSON-RPC response.
+type Response struct {
+    JSONRPC string      `json:"jsonrpc"`
+    ID      interface{} `json:"id"`
+    Result  interface{} `json:"result,omitempty"`
+    Error   *Error      `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+    Code    int    `json:"code"`
+    Message string `json:"message"`
+}
+
+// Notifier delivers a server-initiated notification (e.g. progress on a
+// long-running call) back to whatever transport is driving the request.
+// Transports that have no channel for it (plain request/response) may pass
+// a no-op Notifier.
+type Notifier func(Response)
+
+// Handler processes a single JSON-RPC request and returns its response,
+// emitting any progress notifications to notify as it goes. Transports
+// drive this without knowing anything about the methods it implements.
+type Handler func(req Request, notify Notifier) Response
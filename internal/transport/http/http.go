@@ -0,0 +1,188 @@
+// Package http serves the MCP server over the "Streamable HTTP" binding: a
+// POST /mcp endpoint for JSON-RPC requests and a GET /mcp SSE channel for
+// server-initiated messages, keyed by an Mcp-Session-Id header.
+package http
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "sync"
+
+    "github.com/amillerrr/stock-price-mcp/internal/rpc"
+)
+
+const sessionHeader = "Mcp-Session-Id"
+
+type session struct {
+    notifications chan rpc.Response
+    done          chan struct{}
+}
+
+// Server dispatches JSON-RPC requests arriving over HTTP to a handler and
+// fans out server-initiated notifications (e.g. progress on a long
+// get_historical_prices call) to each session's SSE stream.
+type Server struct {
+    handle rpc.Handler
+
+    mu       sync.Mutex
+    sessions map[string]*session
+}
+
+// NewServer builds an HTTP transport that dispatches requests to handle.
+func NewServer(handle rpc.Handler) *Server {
+    return &Server{
+        handle:   handle,
+        sessions: make(map[string]*session),
+    }
+}
+
+// Mux returns the handler to serve, routing both GET and POST on /mcp.
+func (s *Server) Mux() *http.ServeMux {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/mcp", s.handleMCP)
+    return mux
+}
+
+func (s *Server) handleMCP(w http.ResponseWriter, r *http.Request) {
+    switch r.Method {
+    case http.MethodPost:
+        s.handlePost(w, r)
+    case http.MethodGet:
+        s.handleGet(w, r)
+    default:
+        w.Header().Set("Allow", "GET, POST")
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+    }
+}
+
+// handlePost decodes a single JSON-RPC request and replies with its result
+// as application/json, tagging the response with a session ID so the
+// client can open a GET /mcp SSE channel for any notifications the handler
+// emits while it runs (e.g. historical-fetch progress).
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+    defer r.Body.Close()
+
+    var req rpc.Request
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+        return
+    }
+
+    if req.JSONRPC != "2.0" {
+        req.JSONRPC = "2.0"
+    }
+    if req.ID == nil {
+        req.ID = 0
+    }
+
+    sessionID := r.Header.Get(sessionHeader)
+    if sessionID == "" {
+        sessionID = s.newSessionID()
+    }
+    s.ensureSession(sessionID)
+
+    resp := s.handle(req, func(n rpc.Response) {
+        s.Notify(sessionID, n)
+    })
+
+    w.Header().Set(sessionHeader, sessionID)
+    w.Header().Set("Content-Type", "application/json")
+    if err := json.NewEncoder(w).Encode(resp); err != nil {
+        log.Printf("failed to encode HTTP response: %v", err)
+    }
+}
+
+// handleGet opens an SSE stream that delivers notifications queued for the
+// caller's session until the client disconnects or the server shuts down.
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.Header.Get(sessionHeader)
+    if sessionID == "" {
+        http.Error(w, "missing Mcp-Session-Id", http.StatusBadRequest)
+        return
+    }
+
+    flusher, ok := w.(http.Flusher)
+    if !ok {
+        http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+        return
+    }
+
+    sess := s.ensureSession(sessionID)
+
+    w.Header().Set("Content-Type", "text/event-stream")
+    w.Header().Set("Cache-Control", "no-cache")
+    w.Header().Set("Connection", "keep-alive")
+    w.WriteHeader(http.StatusOK)
+    flusher.Flush()
+
+    for {
+        select {
+        case <-r.Context().Done():
+            return
+        case <-sess.done:
+            return
+        case msg := <-sess.notifications:
+            data, err := json.Marshal(msg)
+            if err != nil {
+                continue
+            }
+            fmt.Fprintf(w, "data: %s\n\n", data)
+            flusher.Flush()
+        }
+    }
+}
+
+func (s *Server) newSessionID() string {
+    buf := make([]byte, 16)
+    rand.Read(buf)
+    return hex.EncodeToString(buf)
+}
+
+func (s *Server) ensureSession(id string) *session {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    sess, ok := s.sessions[id]
+    if !ok {
+        sess = &session{
+            notifications: make(chan rpc.Response, 16),
+            done:          make(chan struct{}),
+        }
+        s.sessions[id] = sess
+    }
+    return sess
+}
+
+// Notify queues a server-initiated message (e.g. progress on a long
+// historical fetch) for delivery over the session's SSE channel. It is a
+// no-op if the session has no open GET /mcp stream.
+func (s *Server) Notify(sessionID string, resp rpc.Response) {
+    s.mu.Lock()
+    sess, ok := s.sessions[sessionID]
+    s.mu.Unlock()
+    if !ok {
+        return
+    }
+
+    select {
+    case sess.notifications <- resp:
+    default:
+    }
+}
+
+// Shutdown closes every open SSE stream so handleGet's goroutines return.
+func (s *Server) Shutdown(ctx context.Context) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, sess := range s.sessions {
+        close(sess.done)
+        delete(s.sessions, id)
+    }
+    return nil
+}
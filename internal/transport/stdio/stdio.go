@@ -0,0 +1,57 @@
+// Package stdio drives the MCP server over newline-delimited JSON-RPC on
+// stdin/stdout, the transport Claude Desktop uses.
+package stdio
+
+import (
+    "encoding/json"
+    "io"
+    "log"
+
+    "github.com/amillerrr/stock-price-mcp/internal/rpc"
+)
+
+// Run decodes JSON-RPC requests from r and writes responses to w until r is
+// exhausted, dispatching each request to handle.
+func Run(r io.Reader, w io.Writer, handle rpc.Handler) {
+    decoder := json.NewDecoder(r)
+    encoder := json.NewEncoder(w)
+
+    for {
+        var req rpc.Request
+        if err := decoder.Decode(&req); err != nil {
+            if err == io.EOF {
+                return
+            }
+            // Log error but continue (don't crash on malformed input)
+            log.Printf("JSON decode error: %v", err)
+            continue
+        }
+
+        // Validate basic request structure
+        if req.JSONRPC != "2.0" {
+            req.JSONRPC = "2.0" // Set default
+        }
+        if req.ID == nil {
+            req.ID = 0 // Set default ID
+        }
+        if req.Method == "" {
+            // Send error response for missing method
+            errorResp := rpc.Response{
+                JSONRPC: "2.0",
+                ID:      req.ID,
+                Error:   &rpc.Error{Code: -32600, Message: "Invalid Request - missing method"},
+            }
+            encoder.Encode(errorResp)
+            continue
+        }
+
+        resp := handle(req, func(n rpc.Response) {
+            if err := encoder.Encode(n); err != nil {
+                log.Printf("Failed to encode notification: %v", err)
+            }
+        })
+        if err := encoder.Encode(resp); err != nil {
+            log.Printf("Failed to encode response: %v", err)
+        }
+    }
+}
@@ -1,42 +1,133 @@
 package main
 
 import (
+    "context"
     "encoding/json"
+    "errors"
+    "flag"
     "fmt"
-    "io"
     "log"
     "net/http"
     "os"
+    "os/signal"
+    "regexp"
     "strings"
+    "sync"
+    "sync/atomic"
+    "syscall"
     "time"
+
+    "github.com/amillerrr/stock-price-mcp/internal/providers"
+    "github.com/amillerrr/stock-price-mcp/internal/rpc"
+    transporthttp "github.com/amillerrr/stock-price-mcp/internal/transport/http"
+    "github.com/amillerrr/stock-price-mcp/internal/transport/stdio"
+)
+
+// defaultCacheTTL and defaultAfterHoursCacheTTL are used when the
+// corresponding environment variable is unset or invalid.
+const (
+    defaultCacheTTL           = 30 * time.Second
+    defaultAfterHoursCacheTTL = 5 * time.Minute
 )
 
-type MCPServer struct{}
+type cacheEntry struct {
+    quote     providers.Quote
+    expiresAt time.Time
+}
+
+// quoteCache holds recently fetched quotes so repeated tool calls for the
+// same symbol within the TTL window don't re-hit the backing provider. The
+// TTL is shorter during regular market hours and longer after-hours, based
+// on the MarketState field the provider returns with each quote.
+type quoteCache struct {
+    mu      sync.RWMutex
+    entries map[string]cacheEntry
+
+    ttl   time.Duration
+    ahTTL time.Duration
+
+    hits   int64
+    misses int64
+}
+
+func newQuoteCache() *quoteCache {
+    return &quoteCache{
+        entries: make(map[string]cacheEntry),
+        ttl:     envDuration("STOCK_MCP_CACHE_TTL", defaultCacheTTL),
+        ahTTL:   envDuration("STOCK_MCP_AH_CACHE_TTL", defaultAfterHoursCacheTTL),
+    }
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+    raw := os.Getenv(key)
+    if raw == "" {
+        return def
+    }
+
+    d, err := time.ParseDuration(raw)
+    if err != nil {
+        log.Printf("invalid duration %q for %s, using default %s", raw, key, def)
+        return def
+    }
+
+    return d
+}
+
+func (c *quoteCache) get(symbol string) (providers.Quote, bool) {
+    c.mu.RLock()
+    entry, ok := c.entries[symbol]
+    c.mu.RUnlock()
+
+    if !ok || time.Now().After(entry.expiresAt) {
+        atomic.AddInt64(&c.misses, 1)
+        return providers.Quote{}, false
+    }
+
+    atomic.AddInt64(&c.hits, 1)
+    return entry.quote, true
+}
+
+func (c *quoteCache) set(symbol string, quote providers.Quote) {
+    ttl := c.ttl
+    if quote.MarketState != "" && quote.MarketState != "REGULAR" {
+        ttl = c.ahTTL
+    }
 
-type JSONRPCRequest struct {
-    JSONRPC string      `json:"jsonrpc"`
-    ID      interface{} `json:"id"`
-    Method  string      `json:"method"`
-    Params  interface{} `json:"params,omitempty"`
+    c.mu.Lock()
+    c.entries[symbol] = cacheEntry{quote: quote, expiresAt: time.Now().Add(ttl)}
+    c.mu.Unlock()
 }
 
-type JSONRPCResponse struct {
-    JSONRPC string      `json:"jsonrpc"`
-    ID      interface{} `json:"id"`
-    Result  interface{} `json:"result,omitempty"`
-    Error   *RPCError   `json:"error,omitempty"`
+func (c *quoteCache) stats() map[string]interface{} {
+    return map[string]interface{}{
+        "hits":   atomic.LoadInt64(&c.hits),
+        "misses": atomic.LoadInt64(&c.misses),
+    }
 }
 
-type RPCError struct {
-    Code    int    `json:"code"`
-    Message string `json:"message"`
+type MCPServer struct {
+    provider providers.PriceProvider
+    cache    *quoteCache
 }
 
+// JSONRPCRequest, JSONRPCResponse, and RPCError alias the shared rpc package
+// types so every transport (stdio, HTTP) and the server agree on the wire
+// format without main depending on either transport package's internals.
+type JSONRPCRequest = rpc.Request
+type JSONRPCResponse = rpc.Response
+type RPCError = rpc.Error
+type Notify = rpc.Notifier
+
 func NewMCPServer() *MCPServer {
-    return &MCPServer{}
+    provider, err := providers.New()
+    if err != nil {
+        log.Printf("failed to init price provider: %v", err)
+    }
+
+    return &MCPServer{provider: provider, cache: newQuoteCache()}
 }
 
-func (s *MCPServer) HandleRequest(req JSONRPCRequest) JSONRPCResponse {
+func (s *MCPServer) HandleRequest(req JSONRPCRequest, notify Notify) JSONRPCResponse {
     // Ensure ID is never null - use 0 if not provided
     id := req.ID
     if id == nil {
@@ -49,7 +140,7 @@ func (s *MCPServer) HandleRequest(req JSONRPCRequest) JSONRPCResponse {
     case "tools/list":
         return s.handleToolsList(req, id)
     case "tools/call":
-        return s.handleToolsCall(req, id)
+        return s.handleToolsCall(req, id, notify)
     default:
         return JSONRPCResponse{
             JSONRPC: "2.0",
@@ -92,6 +183,70 @@ func (s *MCPServer) handleToolsList(req JSONRPCRequest, id interface{}) JSONRPCR
                 "required": []string{"symbol"},
             },
         },
+        {
+            "name":        "get_historical_prices",
+            "description": "Get historical OHLCV price bars for a symbol over a given range and interval using Yahoo Finance",
+            "inputSchema": map[string]interface{}{
+                "type": "object",
+                "properties": map[string]interface{}{
+                    "symbol": map[string]interface{}{
+                        "type":        "string",
+                        "description": "Stock symbol (e.g., AAPL, GOOGL, MSFT, TSLA)",
+                    },
+                    "range": map[string]interface{}{
+                        "type":        "string",
+                        "description": "Time range to fetch: 1d, 5d, 1mo, 3mo, 6mo, 1y, 5y, max (default 1mo)",
+                    },
+                    "interval": map[string]interface{}{
+                        "type":        "string",
+                        "description": "Bar interval: 1m, 5m, 1h, 1d, 1wk (default 1d)",
+                    },
+                },
+                "required": []string{"symbol"},
+            },
+        },
+        {
+            "name":        "get_stock_prices",
+            "description": "Get current stock prices for multiple symbols in a single batch request using Yahoo Finance",
+            "inputSchema": map[string]interface{}{
+                "type": "object",
+                "properties": map[string]interface{}{
+                    "symbols": map[string]interface{}{
+                        "type":        []string{"string", "array"},
+                        "description": "Stock symbols as a comma-separated string or an array (e.g., \"AAPL,GOOGL,MSFT\" or [\"AAPL\", \"GOOGL\"])",
+                    },
+                },
+                "required": []string{"symbols"},
+            },
+        },
+        {
+            "name":        "get_crypto_price",
+            "description": "Get current crypto price using Yahoo Finance symbol conventions (e.g., BTC-USD, ETH-USD)",
+            "inputSchema": map[string]interface{}{
+                "type": "object",
+                "properties": map[string]interface{}{
+                    "symbol": map[string]interface{}{
+                        "type":        "string",
+                        "description": "Crypto symbol, e.g. BTC-USD, ETH-USD, or a bare ticker like BTC (assumed to be BTC-USD)",
+                    },
+                },
+                "required": []string{"symbol"},
+            },
+        },
+        {
+            "name":        "get_fx_rate",
+            "description": "Get a current FX rate using Yahoo Finance symbol conventions (e.g., EURUSD=X, JPY=X)",
+            "inputSchema": map[string]interface{}{
+                "type": "object",
+                "properties": map[string]interface{}{
+                    "symbol": map[string]interface{}{
+                        "type":        "string",
+                        "description": "FX pair, e.g. EURUSD=X, EUR/USD, or JPY=X",
+                    },
+                },
+                "required": []string{"symbol"},
+            },
+        },
     }
 
     return JSONRPCResponse{
@@ -101,7 +256,24 @@ func (s *MCPServer) handleToolsList(req JSONRPCRequest, id interface{}) JSONRPCR
     }
 }
 
-func (s *MCPServer) handleToolsCall(req JSONRPCRequest, id interface{}) JSONRPCResponse {
+// toolHandler is the shape every registered tool's handler implements. notify
+// lets a handler emit progress notifications (e.g. on a long historical
+// fetch) back through whatever transport is driving the request; most
+// handlers simply ignore it.
+type toolHandler func(id interface{}, args map[string]interface{}, notify Notify) JSONRPCResponse
+
+// toolHandlers maps each registered tool name to the handler that serves it.
+func (s *MCPServer) toolHandlers() map[string]toolHandler {
+    return map[string]toolHandler{
+        "get_stock_price":       s.getStockPrice,
+        "get_historical_prices": s.getHistoricalPrices,
+        "get_stock_prices":      s.getStockPrices,
+        "get_crypto_price":      s.getCryptoPrice,
+        "get_fx_rate":           s.getFXRate,
+    }
+}
+
+func (s *MCPServer) handleToolsCall(req JSONRPCRequest, id interface{}, notify Notify) JSONRPCResponse {
     // Safely extract params
     if req.Params == nil {
         return JSONRPCResponse{
@@ -138,248 +310,605 @@ func (s *MCPServer) handleToolsCall(req JSONRPCRequest, id interface{}) JSONRPCR
         }
     }
 
-    switch name {
-    case "get_stock_price":
-        return s.getStockPrice(id, arguments)
-    default:
+    handler, ok := s.toolHandlers()[name]
+    if !ok {
         return JSONRPCResponse{
             JSONRPC: "2.0",
             ID:      id,
             Error:   &RPCError{Code: -32602, Message: "Unknown tool"},
         }
     }
+
+    return handler(id, arguments, notify)
+}
+
+// textResult builds the standard tool result: a human-readable text block,
+// followed by any extraContent items (e.g. raw bars, cache stats) rendered
+// as additional content blocks. Everything a caller needs travels inside
+// the "content" array the MCP tool-call result shape defines, rather than
+// as extra fields on the envelope a spec-compliant client wouldn't look at.
+func textResult(id interface{}, text string, extraContent ...map[string]interface{}) JSONRPCResponse {
+    content := []map[string]interface{}{
+        {
+            "type": "text",
+            "text": text,
+        },
+    }
+    content = append(content, extraContent...)
+
+    return JSONRPCResponse{
+        JSONRPC: "2.0",
+        ID:      id,
+        Result:  map[string]interface{}{"content": content},
+    }
+}
+
+// jsonContentBlock renders v as JSON inside a "text" content block labeled
+// with name, so structured data (raw bars, cache stats) is reachable by any
+// client that only reads the content array, not just ones that know to look
+// for extra top-level result fields.
+func jsonContentBlock(name string, v interface{}) map[string]interface{} {
+    data, err := json.Marshal(v)
+    if err != nil {
+        return map[string]interface{}{"type": "text", "text": fmt.Sprintf("%s: error encoding data: %v", name, err)}
+    }
+    return map[string]interface{}{"type": "text", "text": fmt.Sprintf("%s: %s", name, data)}
 }
 
-func (s *MCPServer) getStockPrice(id interface{}, args map[string]interface{}) JSONRPCResponse {
-    // Safely extract symbol
-    symbolInterface, ok := args["symbol"]
+// extractSymbol pulls the required "symbol" argument out of args, trimming
+// and upper-casing it. Shared by every single-symbol tool.
+func extractSymbol(args map[string]interface{}) (string, error) {
+    v, ok := args["symbol"]
     if !ok {
+        return "", fmt.Errorf("missing symbol parameter")
+    }
+
+    symbol, ok := v.(string)
+    if !ok || strings.TrimSpace(symbol) == "" {
+        return "", fmt.Errorf("symbol must be a non-empty string")
+    }
+
+    return strings.ToUpper(strings.TrimSpace(symbol)), nil
+}
+
+// errNoProvider is returned by quoteFor when the server has no configured
+// PriceProvider, so callers can tell it apart from an ordinary fetch
+// failure (bad symbol, provider down) and report it distinctly.
+var errNoProvider = errors.New("no price provider configured")
+
+// quoteFor fetches a quote for symbol, serving from cache when possible and
+// populating the cache on a successful provider fetch. Every quote-based
+// tool (get_stock_price, get_crypto_price, get_fx_rate) shares this so the
+// provider/crumb/User-Agent plumbing lives in one place.
+func (s *MCPServer) quoteFor(ctx context.Context, symbol string) (providers.Quote, error) {
+    if quote, ok := s.cache.get(symbol); ok {
+        return quote, nil
+    }
+
+    if s.provider == nil {
+        return providers.Quote{}, errNoProvider
+    }
+
+    quote, err := s.provider.Quote(ctx, symbol)
+    if err != nil {
+        return providers.Quote{}, err
+    }
+
+    s.cache.set(symbol, quote)
+    return quote, nil
+}
+
+// quoteErrorResponse maps a quoteFor error to an RPC error response,
+// reporting a missing provider distinctly from an ordinary fetch failure.
+func quoteErrorResponse(id interface{}, symbol string, err error) JSONRPCResponse {
+    if errors.Is(err, errNoProvider) {
         return JSONRPCResponse{
             JSONRPC: "2.0",
             ID:      id,
-            Error:   &RPCError{Code: -32602, Message: "Missing symbol parameter"},
+            Error:   &RPCError{Code: -32603, Message: "No price provider configured"},
         }
     }
 
-    symbol, ok := symbolInterface.(string)
-    if !ok {
+    return JSONRPCResponse{
+        JSONRPC: "2.0",
+        ID:      id,
+        Error:   &RPCError{Code: -32603, Message: fmt.Sprintf("Unable to fetch data for symbol: %s", symbol)},
+    }
+}
+
+func (s *MCPServer) getStockPrice(id interface{}, args map[string]interface{}, notify Notify) JSONRPCResponse {
+    symbol, err := extractSymbol(args)
+    if err != nil {
         return JSONRPCResponse{
             JSONRPC: "2.0",
             ID:      id,
-            Error:   &RPCError{Code: -32602, Message: "Symbol must be a string"},
+            Error:   &RPCError{Code: -32602, Message: err.Error()},
         }
     }
 
-    if symbol == "" {
+    quote, err := s.quoteFor(context.Background(), symbol)
+    if err != nil {
+        return quoteErrorResponse(id, symbol, err)
+    }
+
+    return textResult(id, formatQuote(quote))
+}
+
+// formatQuote renders a provider.Quote as the human-readable tool text,
+// including the richer fundamentals fields when a provider populates them.
+func formatQuote(q providers.Quote) string {
+    change := q.Price - q.PreviousClose
+    changePercent := 0.0
+    if q.PreviousClose != 0 {
+        changePercent = (change / q.PreviousClose) * 100
+    }
+
+    result := fmt.Sprintf(`Stock: %s
+Current Price: $%.2f
+Previous Close: $%.2f
+Change: $%.2f (%.2f%%)`,
+        q.Symbol, q.Price, q.PreviousClose, change, changePercent)
+
+    if q.DayHigh > 0 {
+        result += fmt.Sprintf("\nDay High: $%.2f", q.DayHigh)
+    }
+    if q.DayLow > 0 {
+        result += fmt.Sprintf("\nDay Low: $%.2f", q.DayLow)
+    }
+    if q.Volume > 0 {
+        result += fmt.Sprintf("\nVolume: %.0f", q.Volume)
+    }
+    if q.LongName != "" {
+        result += fmt.Sprintf("\nName: %s", q.LongName)
+    }
+    if q.Exchange != "" {
+        result += fmt.Sprintf("\nExchange: %s", q.Exchange)
+    }
+    if q.MarketCap > 0 {
+        result += fmt.Sprintf("\nMarket Cap: $%.0f", q.MarketCap)
+    }
+    if q.PERatio > 0 {
+        result += fmt.Sprintf("\nP/E Ratio: %.2f", q.PERatio)
+    }
+    if q.EPS != 0 {
+        result += fmt.Sprintf("\nEPS: %.2f", q.EPS)
+    }
+    if q.Bid > 0 {
+        result += fmt.Sprintf("\nBid: $%.2f", q.Bid)
+    }
+    if q.Ask > 0 {
+        result += fmt.Sprintf("\nAsk: $%.2f", q.Ask)
+    }
+    if q.FiftyTwoWeekLow > 0 && q.FiftyTwoWeekHigh > 0 {
+        result += fmt.Sprintf("\n52-Week Range: $%.2f - $%.2f", q.FiftyTwoWeekLow, q.FiftyTwoWeekHigh)
+    }
+    if q.DividendYield > 0 {
+        result += fmt.Sprintf("\nDividend Yield: %.2f%%", q.DividendYield*100)
+    }
+
+    return result
+}
+
+// validHistoricalRanges and validHistoricalIntervals enumerate the range and
+// interval values accepted by get_historical_prices.
+var validHistoricalRanges = map[string]bool{
+    "1d": true, "5d": true, "1mo": true, "3mo": true,
+    "6mo": true, "1y": true, "5y": true, "max": true,
+}
+
+var validHistoricalIntervals = map[string]bool{
+    "1m": true, "5m": true, "1h": true, "1d": true, "1wk": true,
+}
+
+func (s *MCPServer) getHistoricalPrices(id interface{}, args map[string]interface{}, notify Notify) JSONRPCResponse {
+    symbol, err := extractSymbol(args)
+    if err != nil {
         return JSONRPCResponse{
             JSONRPC: "2.0",
             ID:      id,
-            Error:   &RPCError{Code: -32602, Message: "Symbol cannot be empty"},
+            Error:   &RPCError{Code: -32602, Message: err.Error()},
         }
     }
 
-    symbol = strings.ToUpper(symbol)
-    
-    // Try multiple Yahoo Finance endpoints with proper headers
-    client := &http.Client{Timeout: 10 * time.Second}
-    
-    urls := []string{
-        fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s", symbol),
-        fmt.Sprintf("https://query2.finance.yahoo.com/v1/finance/quoteResponse?symbols=%s", symbol),
+    rangeArg := "1mo"
+    if v, ok := args["range"].(string); ok && v != "" {
+        rangeArg = v
     }
-    
-    for _, url := range urls {
-        if result := s.tryEndpoint(client, url, symbol, id); result != nil {
-            return *result
+    if !validHistoricalRanges[rangeArg] {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: fmt.Sprintf("Invalid range: %s", rangeArg)},
         }
     }
-    
-    return JSONRPCResponse{
+
+    interval := "1d"
+    if v, ok := args["interval"].(string); ok && v != "" {
+        interval = v
+    }
+    if !validHistoricalIntervals[interval] {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: fmt.Sprintf("Invalid interval: %s", interval)},
+        }
+    }
+
+    if s.provider == nil {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32603, Message: "No price provider configured"},
+        }
+    }
+
+    notify(JSONRPCResponse{
         JSONRPC: "2.0",
         ID:      id,
-        Error:   &RPCError{Code: -32603, Message: fmt.Sprintf("Unable to fetch data for symbol: %s", symbol)},
+        Result: map[string]interface{}{
+            "type":    "progress",
+            "message": fmt.Sprintf("Fetching %s %s bars for %s...", rangeArg, interval, symbol),
+        },
+    })
+
+    bars, err := s.provider.Historical(context.Background(), symbol, rangeArg, interval)
+    if err != nil || len(bars) == 0 {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32603, Message: fmt.Sprintf("Unable to fetch historical data for symbol: %s", symbol)},
+        }
     }
+
+    return textResult(id, formatHistoricalSummary(symbol, rangeArg, interval, bars), jsonContentBlock("bars", bars))
 }
 
-func (s *MCPServer) tryEndpoint(client *http.Client, url, symbol string, id interface{}) *JSONRPCResponse {
-    req, err := http.NewRequest("GET", url, nil)
-    if err != nil {
-        return nil
+func formatHistoricalSummary(symbol, rangeArg, interval string, bars []providers.Bar) string {
+    first := bars[0]
+    last := bars[len(bars)-1]
+
+    changePercent := 0.0
+    if first.Close != 0 {
+        changePercent = ((last.Close - first.Close) / first.Close) * 100
     }
-    
-    // Critical: Add User-Agent to avoid being blocked
-    req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-    
-    resp, err := client.Do(req)
-    if err != nil {
-        return nil
+
+    minPrice, maxPrice := first.Low, first.High
+    var volumeSum, volumeCount float64
+    for _, bar := range bars {
+        if bar.Low > 0 && (minPrice == 0 || bar.Low < minPrice) {
+            minPrice = bar.Low
+        }
+        if bar.High > maxPrice {
+            maxPrice = bar.High
+        }
+        if bar.Volume > 0 {
+            volumeSum += bar.Volume
+            volumeCount++
+        }
     }
-    defer resp.Body.Close()
 
-    body, err := io.ReadAll(resp.Body)
-    if err != nil {
-        return nil
+    avgVolume := 0.0
+    if volumeCount > 0 {
+        avgVolume = volumeSum / volumeCount
     }
 
-    var data map[string]interface{}
-    if err := json.Unmarshal(body, &data); err != nil {
-        return nil
+    return fmt.Sprintf(`Historical Prices: %s (%s range, %s interval)
+Bars: %d
+First Close: $%.2f
+Last Close: $%.2f
+Change: %.2f%%
+Min Price: $%.2f
+Max Price: $%.2f
+Average Volume: %.0f`,
+        symbol, rangeArg, interval, len(bars), first.Close, last.Close, changePercent, minPrice, maxPrice, avgVolume)
+}
+
+// extractSymbols accepts the "symbols" argument as either a comma-separated
+// string or an array of strings, normalizing each entry to upper case.
+func extractSymbols(args map[string]interface{}) ([]string, error) {
+    raw, ok := args["symbols"]
+    if !ok {
+        return nil, fmt.Errorf("missing symbols parameter")
     }
-    
-    if stockInfo := s.extractStockInfo(data, symbol); stockInfo != "" {
-        result := &JSONRPCResponse{
-            JSONRPC: "2.0",
-            ID:      id,
-            Result: map[string]interface{}{
-                "content": []map[string]interface{}{
-                    {
-                        "type": "text",
-                        "text": stockInfo,
-                    },
-                },
-            },
+
+    var symbols []string
+    switch v := raw.(type) {
+    case string:
+        for _, sym := range strings.Split(v, ",") {
+            if sym = strings.ToUpper(strings.TrimSpace(sym)); sym != "" {
+                symbols = append(symbols, sym)
+            }
+        }
+    case []interface{}:
+        for _, item := range v {
+            sym, ok := item.(string)
+            if !ok {
+                continue
+            }
+            if sym = strings.ToUpper(strings.TrimSpace(sym)); sym != "" {
+                symbols = append(symbols, sym)
+            }
         }
-        return result
+    default:
+        return nil, fmt.Errorf("symbols must be a string or array of strings")
+    }
+
+    if len(symbols) == 0 {
+        return nil, fmt.Errorf("symbols cannot be empty")
     }
-    
-    return nil
+
+    return symbols, nil
 }
 
-func (s *MCPServer) extractStockInfo(data map[string]interface{}, symbol string) string {
-    // Try chart API format
-    if chart, ok := data["chart"].(map[string]interface{}); ok {
-        if results, ok := chart["result"].([]interface{}); ok && len(results) > 0 {
-            if result, ok := results[0].(map[string]interface{}); ok {
-                if meta, ok := result["meta"].(map[string]interface{}); ok {
-                    return s.formatStockData(meta, symbol)
-                }
-            }
+// fetchQuotes resolves quotes for symbols not already cached, preferring a
+// single batched request when the configured provider supports it and
+// falling back to concurrent per-symbol requests otherwise.
+func (s *MCPServer) fetchQuotes(ctx context.Context, symbols []string) map[string]providers.Quote {
+    if batch, ok := s.provider.(providers.BatchQuoter); ok {
+        if quotes, err := batch.QuoteMany(ctx, symbols...); err == nil {
+            return quotes
         }
     }
-    
-    // Try quote response format
-    if quoteResponse, ok := data["quoteResponse"].(map[string]interface{}); ok {
-        if results, ok := quoteResponse["result"].([]interface{}); ok && len(results) > 0 {
-            if result, ok := results[0].(map[string]interface{}); ok {
-                return s.formatQuoteData(result, symbol)
+
+    quotes := make(map[string]providers.Quote)
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+
+    for _, symbol := range symbols {
+        wg.Add(1)
+        go func(symbol string) {
+            defer wg.Done()
+
+            quote, err := s.provider.Quote(ctx, symbol)
+            if err != nil {
+                return
             }
+
+            mu.Lock()
+            quotes[symbol] = quote
+            mu.Unlock()
+        }(symbol)
+    }
+    wg.Wait()
+
+    return quotes
+}
+
+func (s *MCPServer) getStockPrices(id interface{}, args map[string]interface{}, notify Notify) JSONRPCResponse {
+    symbols, err := extractSymbols(args)
+    if err != nil {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: err.Error()},
+        }
+    }
+
+    if s.provider == nil {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32603, Message: "No price provider configured"},
+        }
+    }
+
+    formatted := make(map[string]string, len(symbols))
+
+    var toFetch []string
+    for _, symbol := range symbols {
+        if quote, ok := s.cache.get(symbol); ok {
+            formatted[symbol] = formatQuote(quote)
+            continue
+        }
+        toFetch = append(toFetch, symbol)
+    }
+
+    if len(toFetch) > 0 {
+        for symbol, quote := range s.fetchQuotes(context.Background(), toFetch) {
+            s.cache.set(symbol, quote)
+            formatted[symbol] = formatQuote(quote)
         }
     }
-    
-    return ""
+
+    lines := make([]string, 0, len(symbols))
+    for _, symbol := range symbols {
+        if info, ok := formatted[symbol]; ok {
+            lines = append(lines, info)
+        } else {
+            lines = append(lines, fmt.Sprintf("Stock: %s\nUnable to fetch data", symbol))
+        }
+    }
+
+    return textResult(id, strings.Join(lines, "\n\n"), jsonContentBlock("cache_stats", s.cache.stats()))
 }
 
-func (s *MCPServer) formatStockData(meta map[string]interface{}, symbol string) string {
-    currentPrice, _ := meta["regularMarketPrice"].(float64)
-    previousClose, _ := meta["previousClose"].(float64)
-    dayHigh, _ := meta["regularMarketDayHigh"].(float64)
-    dayLow, _ := meta["regularMarketDayLow"].(float64)
-    volume, _ := meta["regularMarketVolume"].(float64)
-    
-    if currentPrice == 0 {
-        return ""
+// cryptoSymbolPattern and fxSymbolPattern validate Yahoo's symbol
+// conventions after normalization, rejecting anything that clearly isn't a
+// crypto pair or FX pair before it reaches the provider.
+var (
+    cryptoSymbolPattern = regexp.MustCompile(`^[A-Z0-9]{2,10}-[A-Z]{3,4}$`)
+    fxSymbolPattern     = regexp.MustCompile(`^[A-Z]{3,6}=X$`)
+)
+
+// normalizeCryptoSymbol turns a bare ticker like "BTC" into Yahoo's
+// "BTC-USD" form, leaving an already-qualified symbol untouched.
+func normalizeCryptoSymbol(symbol string) string {
+    if strings.Contains(symbol, "-") {
+        return symbol
+    }
+    return symbol + "-USD"
+}
+
+// normalizeFXSymbol rewrites a human-entered pair such as "EUR/USD" or
+// "EURUSD" into Yahoo's "EURUSD=X" form, leaving "JPY=X"-style shorthand
+// (and any symbol already ending in "=X") untouched.
+func normalizeFXSymbol(symbol string) string {
+    symbol = strings.ReplaceAll(symbol, "/", "")
+    if strings.HasSuffix(symbol, "=X") {
+        return symbol
+    }
+    return symbol + "=X"
+}
+
+func (s *MCPServer) getCryptoPrice(id interface{}, args map[string]interface{}, notify Notify) JSONRPCResponse {
+    symbol, err := extractSymbol(args)
+    if err != nil {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: err.Error()},
+        }
+    }
+
+    symbol = normalizeCryptoSymbol(symbol)
+    if !cryptoSymbolPattern.MatchString(symbol) {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: fmt.Sprintf("Invalid crypto symbol: %s", symbol)},
+        }
+    }
+
+    quote, err := s.quoteFor(context.Background(), symbol)
+    if err != nil {
+        return quoteErrorResponse(id, symbol, err)
     }
-    
-    change := currentPrice - previousClose
+
+    return textResult(id, formatCryptoQuote(quote))
+}
+
+// formatCryptoQuote renders a crypto quote, labeling volume as a 24h figure
+// since crypto trades continuously and has no weekend "previous close".
+func formatCryptoQuote(q providers.Quote) string {
+    change := q.Price - q.PreviousClose
     changePercent := 0.0
-    if previousClose != 0 {
-        changePercent = (change / previousClose) * 100
+    if q.PreviousClose != 0 {
+        changePercent = (change / q.PreviousClose) * 100
     }
-    
-    result := fmt.Sprintf(`Stock: %s
+
+    result := fmt.Sprintf(`Crypto: %s
 Current Price: $%.2f
-Previous Close: $%.2f
 Change: $%.2f (%.2f%%)`,
-        symbol, currentPrice, previousClose, change, changePercent)
-    
-    if dayHigh > 0 {
-        result += fmt.Sprintf("\nDay High: $%.2f", dayHigh)
+        q.Symbol, q.Price, change, changePercent)
+
+    if q.DayHigh > 0 {
+        result += fmt.Sprintf("\n24h High: $%.2f", q.DayHigh)
     }
-    if dayLow > 0 {
-        result += fmt.Sprintf("\nDay Low: $%.2f", dayLow)
+    if q.DayLow > 0 {
+        result += fmt.Sprintf("\n24h Low: $%.2f", q.DayLow)
     }
-    if volume > 0 {
-        result += fmt.Sprintf("\nVolume: %.0f", volume)
+    if q.Volume > 0 {
+        result += fmt.Sprintf("\n24h Volume: %.0f", q.Volume)
     }
-    
+
     return result
 }
 
-func (s *MCPServer) formatQuoteData(quote map[string]interface{}, symbol string) string {
-    currentPrice, _ := quote["regularMarketPrice"].(float64)
-    previousClose, _ := quote["regularMarketPreviousClose"].(float64)
-    dayHigh, _ := quote["regularMarketDayHigh"].(float64)
-    dayLow, _ := quote["regularMarketDayLow"].(float64)
-    volume, _ := quote["regularMarketVolume"].(float64)
-    
-    if currentPrice == 0 {
-        return ""
+func (s *MCPServer) getFXRate(id interface{}, args map[string]interface{}, notify Notify) JSONRPCResponse {
+    symbol, err := extractSymbol(args)
+    if err != nil {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: err.Error()},
+        }
     }
-    
-    change := currentPrice - previousClose
-    changePercent := 0.0
-    if previousClose != 0 {
-        changePercent = (change / previousClose) * 100
+
+    symbol = normalizeFXSymbol(symbol)
+    if !fxSymbolPattern.MatchString(symbol) {
+        return JSONRPCResponse{
+            JSONRPC: "2.0",
+            ID:      id,
+            Error:   &RPCError{Code: -32602, Message: fmt.Sprintf("Invalid FX symbol: %s", symbol)},
+        }
     }
-    
-    result := fmt.Sprintf(`Stock: %s
-Current Price: $%.2f
-Previous Close: $%.2f
-Change: $%.2f (%.2f%%)`,
-        symbol, currentPrice, previousClose, change, changePercent)
-    
-    if dayHigh > 0 {
-        result += fmt.Sprintf("\nDay High: $%.2f", dayHigh)
+
+    quote, err := s.quoteFor(context.Background(), symbol)
+    if err != nil {
+        return quoteErrorResponse(id, symbol, err)
+    }
+
+    return textResult(id, formatFXQuote(quote))
+}
+
+// formatFXQuote renders an FX quote at four decimal places, the precision
+// traders expect for a currency pair rather than a $-denominated price.
+func formatFXQuote(q providers.Quote) string {
+    change := q.Price - q.PreviousClose
+    changePercent := 0.0
+    if q.PreviousClose != 0 {
+        changePercent = (change / q.PreviousClose) * 100
     }
-    if dayLow > 0 {
-        result += fmt.Sprintf("\nDay Low: $%.2f", dayLow)
+
+    result := fmt.Sprintf(`FX Rate: %s
+Rate: %.4f
+Previous Close: %.4f
+Change: %.4f (%.2f%%)`,
+        q.Symbol, q.Price, q.PreviousClose, change, changePercent)
+
+    if q.DayHigh > 0 {
+        result += fmt.Sprintf("\nDay High: %.4f", q.DayHigh)
     }
-    if volume > 0 {
-        result += fmt.Sprintf("\nVolume: %.0f", volume)
+    if q.DayLow > 0 {
+        result += fmt.Sprintf("\nDay Low: %.4f", q.DayLow)
     }
-    
+
     return result
 }
 
 func main() {
+    transport := flag.String("transport", "", "transport to use: stdio (default) or http")
+    flag.Parse()
+
+    mode := *transport
+    if mode == "" {
+        mode = os.Getenv("MCP_TRANSPORT")
+    }
+    if mode == "" {
+        mode = "stdio"
+    }
+
     server := NewMCPServer()
-    
-    // Stdio mode for Claude Desktop
-    decoder := json.NewDecoder(os.Stdin)
-    encoder := json.NewEncoder(os.Stdout)
-    
-    for {
-        var req JSONRPCRequest
-        if err := decoder.Decode(&req); err != nil {
-            if err == io.EOF {
-                break
-            }
-            // Log error but continue (don't crash on malformed input)
-            log.Printf("JSON decode error: %v", err)
-            continue
-        }
-        
-        // Validate basic request structure
-        if req.JSONRPC != "2.0" {
-            req.JSONRPC = "2.0" // Set default
-        }
-        if req.ID == nil {
-            req.ID = 0 // Set default ID
-        }
-        if req.Method == "" {
-            // Send error response for missing method
-            errorResp := JSONRPCResponse{
-                JSONRPC: "2.0",
-                ID:      req.ID,
-                Error:   &RPCError{Code: -32600, Message: "Invalid Request - missing method"},
-            }
-            encoder.Encode(errorResp)
-            continue
-        }
-        
-        resp := server.HandleRequest(req)
-        if err := encoder.Encode(resp); err != nil {
-            log.Printf("Failed to encode response: %v", err)
-        }
+
+    switch mode {
+    case "stdio":
+        stdio.Run(os.Stdin, os.Stdout, server.HandleRequest)
+    case "http":
+        runHTTPTransport(server)
+    default:
+        log.Fatalf("unknown transport: %s", mode)
+    }
+}
+
+// runHTTPTransport serves the Streamable HTTP + SSE binding until it
+// receives SIGINT/SIGTERM, then shuts down gracefully.
+func runHTTPTransport(server *MCPServer) {
+    addr := os.Getenv("MCP_HTTP_ADDR")
+    if addr == "" {
+        addr = ":8080"
+    }
+
+    transport := transporthttp.NewServer(server.HandleRequest)
+    httpServer := &http.Server{
+        Addr:    addr,
+        Handler: transport.Mux(),
+    }
+
+    sigCh := make(chan os.Signal, 1)
+    signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+    go func() {
+        <-sigCh
+        log.Println("shutting down MCP HTTP transport")
+
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+
+        transport.Shutdown(ctx)
+        httpServer.Shutdown(ctx)
+    }()
+
+    log.Printf("MCP HTTP transport listening on %s", addr)
+    if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+        log.Fatalf("HTTP transport error: %v", err)
     }
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+    "os"
+    "testing"
+    "time"
+
+    "github.com/amillerrr/stock-price-mcp/internal/providers"
+)
+
+func newTestCache(ttl, ahTTL time.Duration) *quoteCache {
+    return &quoteCache{
+        entries: make(map[string]cacheEntry),
+        ttl:     ttl,
+        ahTTL:   ahTTL,
+    }
+}
+
+func TestQuoteCacheGetSetHitMiss(t *testing.T) {
+    c := newTestCache(time.Hour, time.Hour)
+
+    if _, ok := c.get("AAPL"); ok {
+        t.Fatalf("expected miss on empty cache")
+    }
+
+    c.set("AAPL", providers.Quote{Symbol: "AAPL", Price: 100})
+
+    quote, ok := c.get("AAPL")
+    if !ok {
+        t.Fatalf("expected hit after set")
+    }
+    if quote.Price != 100 {
+        t.Fatalf("got price %v, want 100", quote.Price)
+    }
+
+    stats := c.stats()
+    if stats["hits"].(int64) != 1 {
+        t.Fatalf("got %v hits, want 1", stats["hits"])
+    }
+    if stats["misses"].(int64) != 1 {
+        t.Fatalf("got %v misses, want 1", stats["misses"])
+    }
+}
+
+func TestQuoteCacheExpiry(t *testing.T) {
+    c := newTestCache(10*time.Millisecond, time.Hour)
+
+    c.set("AAPL", providers.Quote{Symbol: "AAPL", Price: 100, MarketState: "REGULAR"})
+
+    if _, ok := c.get("AAPL"); !ok {
+        t.Fatalf("expected hit immediately after set")
+    }
+
+    time.Sleep(20 * time.Millisecond)
+
+    if _, ok := c.get("AAPL"); ok {
+        t.Fatalf("expected entry to have expired")
+    }
+}
+
+func TestQuoteCacheUsesAfterHoursTTL(t *testing.T) {
+    c := newTestCache(time.Hour, 10*time.Millisecond)
+
+    c.set("AAPL", providers.Quote{Symbol: "AAPL", Price: 100, MarketState: "POST"})
+
+    time.Sleep(20 * time.Millisecond)
+
+    if _, ok := c.get("AAPL"); ok {
+        t.Fatalf("expected after-hours entry to expire using the shorter ahTTL")
+    }
+}
+
+func TestEnvDuration(t *testing.T) {
+    const key = "STOCK_MCP_TEST_DURATION"
+
+    t.Run("unset uses default", func(t *testing.T) {
+        os.Unsetenv(key)
+        if got := envDuration(key, 5*time.Second); got != 5*time.Second {
+            t.Fatalf("got %v, want 5s", got)
+        }
+    })
+
+    t.Run("valid duration overrides default", func(t *testing.T) {
+        os.Setenv(key, "2m")
+        defer os.Unsetenv(key)
+        if got := envDuration(key, 5*time.Second); got != 2*time.Minute {
+            t.Fatalf("got %v, want 2m", got)
+        }
+    })
+
+    t.Run("invalid duration falls back to default", func(t *testing.T) {
+        os.Setenv(key, "not-a-duration")
+        defer os.Unsetenv(key)
+        if got := envDuration(key, 5*time.Second); got != 5*time.Second {
+            t.Fatalf("got %v, want default 5s", got)
+        }
+    })
+}